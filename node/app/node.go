@@ -44,9 +44,23 @@ func (n *Node) Start() {
 	}
 }
 
+// dhtCloser is implemented by consensus engines that bootstrap their own
+// DHT peer directory (currently the ceremony engine). It's checked with a
+// type assertion rather than added to consensus.ConsensusEngine so engines
+// without a DHT don't need a no-op implementation.
+type dhtCloser interface {
+	CloseDHT() error
+}
+
 func (n *Node) Stop() {
 	err := <-n.engine.Stop(false)
 	if err != nil {
 		panic(err)
 	}
+
+	if closer, ok := n.engine.(dhtCloser); ok {
+		if err := closer.CloseDHT(); err != nil {
+			panic(err)
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,14 @@
+package config
+
+// minimumVersion is the lowest peer version this build will accept sync and
+// gossip traffic from. It is bumped for hard-fork releases so that old
+// binaries are cleanly excluded from the mesh rather than left wired in
+// with message handlers they can no longer safely satisfy.
+var minimumVersion = []byte{2, 0, 0}
+
+// GetMinimumVersion returns the build-time-configured minimum peer version,
+// encoded as major/minor/patch bytes comparable with bytes.Compare against
+// the version a peer announces in its CeremonyPeerListAnnounce.
+func GetMinimumVersion() []byte {
+	return minimumVersion
+}
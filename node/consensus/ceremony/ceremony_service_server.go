@@ -0,0 +1,128 @@
+package ceremony
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+	"source.quilibrium.com/quilibrium/monorepo/node/store"
+)
+
+// maxConcurrentSyncPeers bounds how many peers may be streamed clock frame
+// history from this node at once, since each one costs a full range scan
+// of the clock store.
+const maxConcurrentSyncPeers = 4
+
+// ErrNoNewFrames is returned (and, over the wire, signalled by a stream EOF)
+// once a sync server has no frame past the requested range to offer.
+var ErrNoNewFrames = errors.New("no new frames")
+
+// ErrPeerSaturated is returned when a sync peer rejected the request
+// because it was already serving maxConcurrentSyncPeers. Unlike
+// ErrNoNewFrames, this says nothing about whether the peer actually has
+// newer frames, so sync-target selection should fall back to the next
+// ranked candidate rather than conclude syncing is complete.
+var ErrPeerSaturated = errors.New("sync peer is saturated")
+
+// CeremonyService implements protobufs.CeremonyServiceServer, streaming
+// ordered, deduplicated clock frame batches out of the clockStore.
+type CeremonyService struct {
+	protobufs.UnimplementedCeremonyServiceServer
+
+	engine *CeremonyDataClockConsensusEngine
+
+	currentReceivingSyncPeers int32
+}
+
+func NewCeremonyService(
+	engine *CeremonyDataClockConsensusEngine,
+) *CeremonyService {
+	return &CeremonyService{engine: engine}
+}
+
+// GetCompressedSyncFrames streams CompressedClockFramesResponse batches to
+// the requesting peer, enforcing maxConcurrentSyncPeers.
+func (s *CeremonyService) GetCompressedSyncFrames(
+	request *protobufs.ClockFramesRequest,
+	server protobufs.CeremonyService_GetCompressedSyncFramesServer,
+) error {
+	if atomic.AddInt32(&s.currentReceivingSyncPeers, 1) > maxConcurrentSyncPeers {
+		atomic.AddInt32(&s.currentReceivingSyncPeers, -1)
+		s.engine.logger.Debug(
+			"rejecting sync request, already at capacity",
+			zap.Uint64("from_frame_number", request.FromFrameNumber),
+		)
+		return server.Send(&protobufs.CompressedClockFramesResponse{
+			Filter:          request.Filter,
+			FromFrameNumber: 0,
+			ToFrameNumber:   0,
+			Frames:          []*protobufs.CompressedClockFrame{},
+			Saturated:       true,
+		})
+	}
+	defer atomic.AddInt32(&s.currentReceivingSyncPeers, -1)
+
+	from := request.FromFrameNumber
+	to := request.ToFrameNumber
+
+	for {
+		batch, next, err := s.engine.collectClockFrameBatch(
+			request.Filter,
+			from,
+			to,
+		)
+		if err != nil {
+			if errors.Is(err, store.ErrNotFound) {
+				return nil
+			}
+			return errors.Wrap(err, "get compressed sync frames")
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		compressed, err := compressClockFramesResponse(&protobufs.ClockFramesResponse{
+			Filter:          request.Filter,
+			FromFrameNumber: from,
+			ToFrameNumber:   next - 1,
+			ClockFrames:     batch,
+		})
+		if err != nil {
+			return errors.Wrap(err, "get compressed sync frames")
+		}
+
+		if err := server.Send(compressed); err != nil {
+			return errors.Wrap(err, "get compressed sync frames")
+		}
+
+		if to != 0 && next > to {
+			return nil
+		}
+
+		from = next
+	}
+}
+
+// GetPeerInfo lists the peers currently tracked in e.peerMap, for admin
+// tooling diagnosing a hard-fork rollout.
+func (s *CeremonyService) GetPeerInfo(
+	ctx context.Context,
+	request *protobufs.GetPeerInfoRequest,
+) (*protobufs.GetPeerInfoResponse, error) {
+	s.engine.peerMapMx.RLock()
+	defer s.engine.peerMapMx.RUnlock()
+
+	peers := make([]*protobufs.CeremonyPeer, 0, len(s.engine.peerMap))
+	for _, info := range s.engine.peerMap {
+		peers = append(peers, &protobufs.CeremonyPeer{
+			PeerId:   info.peerId,
+			Version:  info.version,
+			MaxFrame: info.maxFrame,
+		})
+	}
+
+	return &protobufs.GetPeerInfoResponse{PeerInfo: peers}, nil
+}
@@ -0,0 +1,138 @@
+package ceremony
+
+import (
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+	"source.quilibrium.com/quilibrium/monorepo/node/store"
+)
+
+// maxFramesPerBatch bounds how many frame numbers collectClockFrameBatch
+// will cover before handing a batch back to the CeremonyService stream, so a
+// single deep resync doesn't hold the whole range in memory at once. Forked
+// candidate frames sharing a frame number all count as one step, so a batch
+// can hold more than maxFramesPerBatch frames.
+const maxFramesPerBatch = 32
+
+// collectClockFrameBatch walks finalized frames and, once the finalized
+// chain is exhausted, candidate frames, starting at from and stopping at
+// to (or after maxFramesPerBatch frame numbers, whichever comes first). It
+// returns the collected frames and the frame number the next batch should
+// resume from. The caller (CeremonyService.GetCompressedSyncFrames) is
+// responsible for streaming these to the peer; this method performs no
+// I/O beyond reading the clock store.
+func (e *CeremonyDataClockConsensusEngine) collectClockFrameBatch(
+	filter []byte,
+	from uint64,
+	to uint64,
+) ([]*protobufs.ClockFrame, uint64, error) {
+	base, _, err := e.clockStore.GetDataClockFrame(filter, from)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, "collect clock frame batch")
+	}
+
+	limit := to
+	if limit == 0 || limit-from >= maxFramesPerBatch {
+		limit = from + maxFramesPerBatch - 1
+	}
+
+	set := []*protobufs.ClockFrame{base}
+	noMoreFinalized := false
+	searchSpan := []*protobufs.ClockFrame{base}
+	currentNumber := 1
+
+	for len(searchSpan) != 0 && from+uint64(currentNumber) <= limit {
+		e.logger.Debug(
+			"scanning frames to add to sync batch",
+			zap.Binary("filter", filter),
+			zap.Uint64("from", from),
+			zap.Uint64("limit", limit),
+			zap.Uint64("current_number", uint64(currentNumber)),
+		)
+		nextSpan := []*protobufs.ClockFrame{}
+		for _, s := range searchSpan {
+			selector, err := s.GetSelector()
+			if err != nil {
+				return nil, 0, errors.Wrap(err, "collect clock frame batch")
+			}
+
+			if !noMoreFinalized {
+				frame, _, err := e.clockStore.GetDataClockFrame(
+					s.Filter,
+					s.FrameNumber+1,
+				)
+				if err != nil {
+					if errors.Is(err, store.ErrNotFound) {
+						noMoreFinalized = true
+					} else {
+						e.logger.Error(
+							"fetching clock frame produced error",
+							zap.Uint64("frame_number", s.FrameNumber+1),
+						)
+						return nil, 0, errors.Wrap(err, "collect clock frame batch")
+					}
+				} else {
+					nextSpan = append(nextSpan, frame)
+					set = append(set, frame)
+				}
+			}
+
+			if noMoreFinalized {
+				iter, err := e.clockStore.RangeCandidateDataClockFrames(
+					s.Filter,
+					selector.Bytes(),
+					s.FrameNumber+1,
+				)
+				if err != nil {
+					e.logger.Error(
+						"iterating candidate clock frames produced error",
+						zap.Binary("parent_selector", s.ParentSelector),
+						zap.Uint64("frame_number", s.FrameNumber+1),
+					)
+					return nil, 0, errors.Wrap(err, "collect clock frame batch")
+				}
+
+				for iter.First(); iter.Valid(); iter.Next() {
+					frame, err := iter.Value()
+					if err != nil {
+						iter.Close()
+						e.logger.Error(
+							"reading candidate clock frame produced error",
+							zap.Binary("parent_selector", selector.Bytes()),
+							zap.Uint64("frame_number", s.FrameNumber+1),
+						)
+						return nil, 0, errors.Wrap(err, "collect clock frame batch")
+					}
+
+					nextSpan = append(nextSpan, frame)
+					set = append(set, frame)
+				}
+
+				iter.Close()
+			}
+		}
+		currentNumber++
+		searchSpan = nextSpan
+	}
+
+	return set, nextFrameNumberAfterBatch(set), nil
+}
+
+// nextFrameNumberAfterBatch returns the frame number the next batch should
+// resume from, i.e. one past the highest frame number actually present in
+// set. This must be computed from the frames themselves rather than from
+// len(set): once the walk above falls back to RangeCandidateDataClockFrames
+// (the noMoreFinalized branch), a single frame-number step can yield
+// several forked candidate frames, so len(set) counts more entries than
+// frame-number steps advanced. Using len(set) as the cursor would skip
+// ahead of frame numbers that were never actually fetched or sent.
+func nextFrameNumberAfterBatch(set []*protobufs.ClockFrame) uint64 {
+	highest := uint64(0)
+	for _, frame := range set {
+		if frame.FrameNumber > highest {
+			highest = frame.FrameNumber
+		}
+	}
+
+	return highest + 1
+}
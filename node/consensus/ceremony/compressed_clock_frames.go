@@ -0,0 +1,240 @@
+package ceremony
+
+import (
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/proto"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+)
+
+// clockFrameHeaderSize is the fixed-size portion of ClockFrame.Input that
+// precedes its repeated 74-byte aggregate commitments.
+const clockFrameHeaderSize = 516
+
+// clockFrameCommitmentSize is the size, in bytes, of a single aggregate
+// commitment (and of an InclusionAggregateProof's own Proof point).
+const clockFrameCommitmentSize = 74
+
+// commitmentTable deduplicates commitment-shaped byte strings (aggregate
+// commitments and proof points) into a single table of handles shared
+// across every frame in a response. Inclusion commitments are not stored
+// here; they go in the separate inclusion proof table.
+type commitmentTable struct {
+	handles map[string]uint32
+	entries [][]byte
+}
+
+func newCommitmentTable() *commitmentTable {
+	return &commitmentTable{handles: make(map[string]uint32)}
+}
+
+func (t *commitmentTable) intern(value []byte) uint32 {
+	key := string(value)
+	if handle, ok := t.handles[key]; ok {
+		return handle
+	}
+
+	handle := uint32(len(t.entries))
+	t.handles[key] = handle
+	t.entries = append(t.entries, append([]byte{}, value...))
+	return handle
+}
+
+// compressClockFramesResponse rewrites response into its deduplicated wire
+// form: every distinct aggregate commitment / proof point is emitted once
+// into a shared table, each inclusion commitment is emitted once per
+// (commitment handle, segment index) pair into the inclusion proof table,
+// and each frame references those tables by handle instead of repeating
+// the bytes.
+func compressClockFramesResponse(
+	response *protobufs.ClockFramesResponse,
+) (*protobufs.CompressedClockFramesResponse, error) {
+	commitments := newCommitmentTable()
+	inclusionProofs := map[inclusionProofKey]uint32{}
+	inclusionProofTable := []*protobufs.CompressedInclusionProof{}
+
+	compressedFrames := make(
+		[]*protobufs.CompressedClockFrame,
+		0,
+		len(response.ClockFrames),
+	)
+
+	for _, frame := range response.ClockFrames {
+		if len(frame.Input) < clockFrameHeaderSize {
+			return nil, errors.New("compress clock frames response: frame input too short")
+		}
+		if (len(frame.Input)-clockFrameHeaderSize)%clockFrameCommitmentSize != 0 {
+			return nil, errors.New(
+				"compress clock frames response: frame input is not commitment-aligned",
+			)
+		}
+
+		header := proto.Clone(frame).(*protobufs.ClockFrame)
+		header.Input = append([]byte{}, frame.Input[:clockFrameHeaderSize]...)
+		header.AggregateProofs = nil
+
+		headerBytes, err := proto.Marshal(header)
+		if err != nil {
+			return nil, errors.Wrap(err, "compress clock frames response")
+		}
+
+		commitmentCount := (len(frame.Input) - clockFrameHeaderSize) /
+			clockFrameCommitmentSize
+		commitmentHandles := make([]uint32, 0, commitmentCount)
+		for i := 0; i < commitmentCount; i++ {
+			start := clockFrameHeaderSize + i*clockFrameCommitmentSize
+			commitmentHandles = append(
+				commitmentHandles,
+				commitments.intern(frame.Input[start:start+clockFrameCommitmentSize]),
+			)
+		}
+
+		compressedProofs := make(
+			[]*protobufs.CompressedAggregateProof,
+			0,
+			len(frame.AggregateProofs),
+		)
+		for i, proof := range frame.AggregateProofs {
+			proofHandle := commitmentHandles[i]
+
+			inclusionHandles := make(
+				[]uint32,
+				0,
+				len(proof.GetInclusionCommitments()),
+			)
+			for segmentIdx, commit := range proof.GetInclusionCommitments() {
+				key := inclusionProofKey{
+					commitmentHandle: proofHandle,
+					segmentHandle:    uint32(segmentIdx),
+				}
+
+				handle, ok := inclusionProofs[key]
+				if !ok {
+					handle = uint32(len(inclusionProofTable))
+					inclusionProofs[key] = handle
+					inclusionProofTable = append(
+						inclusionProofTable,
+						&protobufs.CompressedInclusionProof{
+							CommitmentHandle: key.commitmentHandle,
+							SegmentHandle:    key.segmentHandle,
+							Proof:            commit,
+						},
+					)
+				}
+
+				inclusionHandles = append(inclusionHandles, handle)
+			}
+
+			compressedProofs = append(
+				compressedProofs,
+				&protobufs.CompressedAggregateProof{
+					ProofHandle:           commitments.intern(proof.Proof),
+					InclusionProofHandles: inclusionHandles,
+				},
+			)
+		}
+
+		compressedFrames = append(compressedFrames, &protobufs.CompressedClockFrame{
+			Header:            headerBytes,
+			CommitmentHandles: commitmentHandles,
+			AggregateProofs:   compressedProofs,
+		})
+	}
+
+	return &protobufs.CompressedClockFramesResponse{
+		Filter:              response.Filter,
+		FromFrameNumber:     response.FromFrameNumber,
+		ToFrameNumber:       response.ToFrameNumber,
+		CommitmentTable:     commitments.entries,
+		InclusionProofTable: inclusionProofTable,
+		Frames:              compressedFrames,
+	}, nil
+}
+
+// decompressClockFramesResponse rehydrates full ClockFrame objects from a
+// CompressedClockFramesResponse, producing bit-for-bit the same
+// ClockFramesResponse that compressClockFramesResponse was given, so that
+// VerifyDataClockFrame/VerifyAggregateProof can run unchanged.
+func decompressClockFramesResponse(
+	compressed *protobufs.CompressedClockFramesResponse,
+) (*protobufs.ClockFramesResponse, error) {
+	frames := make([]*protobufs.ClockFrame, 0, len(compressed.Frames))
+
+	for _, cf := range compressed.Frames {
+		frame := &protobufs.ClockFrame{}
+		if err := proto.Unmarshal(cf.Header, frame); err != nil {
+			return nil, errors.Wrap(err, "decompress clock frames response")
+		}
+
+		input := append([]byte{}, frame.Input...)
+		for _, handle := range cf.CommitmentHandles {
+			commitment, err := lookupCommitment(compressed, handle)
+			if err != nil {
+				return nil, errors.Wrap(err, "decompress clock frames response")
+			}
+			input = append(input, commitment...)
+		}
+		frame.Input = input
+
+		proofs := make(
+			[]*protobufs.InclusionAggregateProof,
+			0,
+			len(cf.AggregateProofs),
+		)
+		for _, cp := range cf.AggregateProofs {
+			proofBytes, err := lookupCommitment(compressed, cp.ProofHandle)
+			if err != nil {
+				return nil, errors.Wrap(err, "decompress clock frames response")
+			}
+
+			commits := make(
+				[]*protobufs.InclusionCommitment,
+				0,
+				len(cp.InclusionProofHandles),
+			)
+			for _, handle := range cp.InclusionProofHandles {
+				if int(handle) >= len(compressed.InclusionProofTable) {
+					return nil, errors.New(
+						"decompress clock frames response: inclusion proof handle out of range",
+					)
+				}
+				commits = append(
+					commits,
+					compressed.InclusionProofTable[handle].Proof,
+				)
+			}
+
+			proofs = append(proofs, &protobufs.InclusionAggregateProof{
+				Proof:                proofBytes,
+				InclusionCommitments: commits,
+			})
+		}
+		frame.AggregateProofs = proofs
+
+		frames = append(frames, frame)
+	}
+
+	return &protobufs.ClockFramesResponse{
+		Filter:          compressed.Filter,
+		FromFrameNumber: compressed.FromFrameNumber,
+		ToFrameNumber:   compressed.ToFrameNumber,
+		ClockFrames:     frames,
+	}, nil
+}
+
+type inclusionProofKey struct {
+	commitmentHandle uint32
+	segmentHandle    uint32
+}
+
+func lookupCommitment(
+	compressed *protobufs.CompressedClockFramesResponse,
+	handle uint32,
+) ([]byte, error) {
+	if int(handle) >= len(compressed.CommitmentTable) {
+		return nil, errors.New(
+			"decompress clock frames response: commitment handle out of range",
+		)
+	}
+
+	return compressed.CommitmentTable[handle], nil
+}
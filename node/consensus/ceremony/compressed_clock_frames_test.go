@@ -0,0 +1,150 @@
+package ceremony
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+)
+
+func makeTestFrame(frameNumber uint64, commitments ...string) *protobufs.ClockFrame {
+	input := make([]byte, clockFrameHeaderSize)
+	for i := range input {
+		input[i] = byte(frameNumber + uint64(i))
+	}
+
+	proofs := make([]*protobufs.InclusionAggregateProof, 0, len(commitments))
+	for _, c := range commitments {
+		commitment := []byte(c)
+		for len(commitment) < clockFrameCommitmentSize {
+			commitment = append(commitment, 0)
+		}
+		input = append(input, commitment...)
+
+		proofs = append(proofs, &protobufs.InclusionAggregateProof{
+			Proof: commitment,
+			InclusionCommitments: []*protobufs.InclusionCommitment{
+				{
+					TypeUrl:    "test-commitment",
+					Data:       []byte(c),
+					Commitment: commitment,
+				},
+			},
+		})
+	}
+
+	return &protobufs.ClockFrame{
+		Filter:          []byte("test-filter"),
+		FrameNumber:     frameNumber,
+		Input:           input,
+		AggregateProofs: proofs,
+	}
+}
+
+// makeForkedTestFrame builds a frame sharing frameNumber with another, as
+// RangeCandidateDataClockFrames can return once the finalized chain is
+// exhausted and the walk falls back to candidate/fork frames. variant
+// distinguishes it from siblings at the same frame number.
+func makeForkedTestFrame(
+	frameNumber uint64,
+	variant byte,
+	commitments ...string,
+) *protobufs.ClockFrame {
+	frame := makeTestFrame(frameNumber, commitments...)
+	frame.ParentSelector = []byte{variant}
+	return frame
+}
+
+func TestCompressClockFramesResponseRoundTrip(t *testing.T) {
+	response := &protobufs.ClockFramesResponse{
+		Filter:          []byte("test-filter"),
+		FromFrameNumber: 10,
+		ToFrameNumber:   12,
+		ClockFrames: []*protobufs.ClockFrame{
+			makeTestFrame(10, "alice", "bob"),
+			makeTestFrame(11, "alice", "carol"),
+			makeTestFrame(12, "bob"),
+		},
+	}
+
+	compressed, err := compressClockFramesResponse(response)
+	if err != nil {
+		t.Fatalf("compressClockFramesResponse: %v", err)
+	}
+
+	if len(compressed.CommitmentTable) != 3 {
+		t.Fatalf(
+			"expected 3 distinct commitments in table, got %d",
+			len(compressed.CommitmentTable),
+		)
+	}
+
+	decompressed, err := decompressClockFramesResponse(compressed)
+	if err != nil {
+		t.Fatalf("decompressClockFramesResponse: %v", err)
+	}
+
+	want, err := proto.Marshal(response)
+	if err != nil {
+		t.Fatalf("marshal original response: %v", err)
+	}
+
+	got, err := proto.Marshal(decompressed)
+	if err != nil {
+		t.Fatalf("marshal decompressed response: %v", err)
+	}
+
+	if string(want) != string(got) {
+		t.Fatalf("decompressed response did not round-trip bit-for-bit")
+	}
+}
+
+// TestCompressClockFramesResponseRoundTripWithForkedFrames covers a batch
+// that includes multiple candidate frames sharing a frame number, as
+// collectClockFrameBatch produces once it falls back from finalized frames
+// to RangeCandidateDataClockFrames. The batch has 4 frames across frame
+// numbers 10-12; nextFrameNumberAfterBatch must resume at 13, not at
+// len(frames)+10 (14), which was the bug this test guards against.
+func TestCompressClockFramesResponseRoundTripWithForkedFrames(t *testing.T) {
+	frames := []*protobufs.ClockFrame{
+		makeTestFrame(10, "alice"),
+		makeForkedTestFrame(11, 1, "alice", "bob"),
+		makeForkedTestFrame(11, 2, "carol"),
+		makeForkedTestFrame(12, 1, "bob"),
+	}
+
+	if next := nextFrameNumberAfterBatch(frames); next != 13 {
+		t.Fatalf("expected next frame number 13, got %d", next)
+	}
+
+	response := &protobufs.ClockFramesResponse{
+		Filter:          []byte("test-filter"),
+		FromFrameNumber: 10,
+		ToFrameNumber:   12,
+		ClockFrames:     frames,
+	}
+
+	compressed, err := compressClockFramesResponse(response)
+	if err != nil {
+		t.Fatalf("compressClockFramesResponse: %v", err)
+	}
+
+	decompressed, err := decompressClockFramesResponse(compressed)
+	if err != nil {
+		t.Fatalf("decompressClockFramesResponse: %v", err)
+	}
+
+	want, err := proto.Marshal(response)
+	if err != nil {
+		t.Fatalf("marshal original response: %v", err)
+	}
+
+	got, err := proto.Marshal(decompressed)
+	if err != nil {
+		t.Fatalf("marshal decompressed response: %v", err)
+	}
+
+	if string(want) != string(got) {
+		t.Fatalf("decompressed forked-frame response did not round-trip bit-for-bit")
+	}
+}
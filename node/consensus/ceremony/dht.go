@@ -0,0 +1,79 @@
+package ceremony
+
+import (
+	"context"
+	"sync"
+
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// dhtBootstrapOnce guards bootstrapDHT so the concurrent peer lookups
+// handlePeerListAnnounce now runs (see peer_version.go) only stand up the
+// DHT once, the first time confirmPeerReachable needs it.
+var dhtBootstrapOnce sync.Once
+
+// bootstrapDHT stands up a Kademlia DHT over the engine's libp2p host and
+// joins the mesh's bootstrap peers, giving the engine a source of peer
+// reachability that isn't just whatever a CeremonyPeerListAnnounce claims.
+func (e *CeremonyDataClockConsensusEngine) bootstrapDHT(
+	ctx context.Context,
+) error {
+	kad, err := dht.New(ctx, e.pubSub.GetHost())
+	if err != nil {
+		return errors.Wrap(err, "bootstrap dht")
+	}
+
+	if err := kad.Bootstrap(ctx); err != nil {
+		return errors.Wrap(err, "bootstrap dht")
+	}
+
+	e.dht = kad
+	return nil
+}
+
+// CloseDHT is exported so app.Node.Stop can close it via a type assertion.
+func (e *CeremonyDataClockConsensusEngine) CloseDHT() error {
+	if e.dht == nil {
+		return nil
+	}
+
+	return e.dht.Close()
+}
+
+// confirmPeerReachable cross-checks a peer-list announcement against the
+// DHT before it is admitted into e.peerMap: pubsub peer-list announcements
+// are easily spoofed and routinely include peers that have long since left
+// the mesh, where a DHT FindPeer will fail.
+func (e *CeremonyDataClockConsensusEngine) confirmPeerReachable(
+	ctx context.Context,
+	peerID []byte,
+) bool {
+	dhtBootstrapOnce.Do(func() {
+		if err := e.bootstrapDHT(ctx); err != nil {
+			e.logger.Error("could not bootstrap dht", zap.Error(err))
+		}
+	})
+
+	if e.dht == nil {
+		return false
+	}
+
+	id, err := peer.IDFromBytes(peerID)
+	if err != nil {
+		return false
+	}
+
+	if _, err := e.dht.FindPeer(ctx, id); err != nil {
+		e.logger.Debug(
+			"dht could not confirm peer reachability",
+			zap.Binary("peer_id", peerID),
+			zap.Error(err),
+		)
+		return false
+	}
+
+	return true
+}
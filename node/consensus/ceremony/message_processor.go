@@ -0,0 +1,57 @@
+package ceremony
+
+import (
+	"sync"
+
+	"go.uber.org/zap"
+	"source.quilibrium.com/quilibrium/monorepo/go-libp2p-blossomsub/pb"
+)
+
+// messageProcessorChCap bounds how many pending gossip messages the engine
+// will hold before it starts dropping the oldest one to make room.
+const messageProcessorChCap = 128
+
+// startMessageProcessorOnce guards messageProcessorCh's initialization and
+// the single runMessageHandler goroutine draining it. enqueueSyncMessage is
+// reachable from more than one pubsub subscription (the main topic and each
+// per-peer proving-key topic, see handleProvingKeyRequest), so the
+// lazy-start below needs this rather than a plain nil check.
+var startMessageProcessorOnce sync.Once
+
+// enqueueSyncMessage hands message off to runMessageHandler's queue
+// without blocking the pubsub receive goroutine, dropping the oldest
+// queued message (and striking its sender) to make room if the queue is
+// full.
+func (e *CeremonyDataClockConsensusEngine) enqueueSyncMessage(
+	message *pb.Message,
+) {
+	startMessageProcessorOnce.Do(func() {
+		e.messageProcessorCh = make(chan *pb.Message, messageProcessorChCap)
+		go e.runMessageHandler()
+	})
+
+	select {
+	case e.messageProcessorCh <- message:
+	default:
+		dropped := <-e.messageProcessorCh
+		e.logger.Debug(
+			"dropping oldest queued message to make room",
+			zap.Binary("dropped_from", dropped.From),
+			zap.Binary("from", message.From),
+		)
+		e.recordUncooperativeStrike(dropped.From)
+		e.messageProcessorCh <- message
+	}
+}
+
+// runMessageHandler drains messageProcessorCh and dispatches each message
+// to its protocol handler, keeping BLS48581 decompression, FFTs, aggregate
+// proof verification, and store writes off the pubsub receive goroutine
+// that handleSync runs on.
+func (e *CeremonyDataClockConsensusEngine) runMessageHandler() {
+	for message := range e.messageProcessorCh {
+		if err := e.dispatchSyncMessage(message); err != nil {
+			e.logger.Error("error dispatching sync message", zap.Error(err))
+		}
+	}
+}
@@ -17,6 +17,11 @@ import (
 	"source.quilibrium.com/quilibrium/monorepo/node/tries"
 )
 
+// handleSync is the pubsub receive callback. It only validates the outer
+// envelope (self-message and peer-version checks) before handing the raw
+// message to the bounded message processor queue; the actual unmarshal and
+// protocol dispatch happens on runMessageHandler's goroutine so that a
+// slow or heavy payload can't stall gossip delivery for this topic.
 func (e *CeremonyDataClockConsensusEngine) handleSync(
 	message *pb.Message,
 ) error {
@@ -30,6 +35,27 @@ func (e *CeremonyDataClockConsensusEngine) handleSync(
 		return nil
 	}
 
+	e.ensureSyncServicesStarted()
+
+	if e.peerBelowMinimumVersion(message.From) {
+		e.logger.Debug(
+			"dropping message from peer below minimum version",
+			zap.Binary("from", message.From),
+		)
+		e.recordUncooperativeStrike(message.From)
+		return nil
+	}
+
+	e.enqueueSyncMessage(message)
+	return nil
+}
+
+// dispatchSyncMessage unmarshals a queued message and routes it to its
+// protocol handler. It runs on runMessageHandler's goroutine, never on the
+// pubsub receive goroutine.
+func (e *CeremonyDataClockConsensusEngine) dispatchSyncMessage(
+	message *pb.Message,
+) error {
 	msg := &protobufs.Message{}
 
 	if err := proto.Unmarshal(message.Data, msg); err != nil {
@@ -51,16 +77,12 @@ func (e *CeremonyDataClockConsensusEngine) handleSync(
 		); err != nil {
 			return errors.Wrap(err, "handle sync")
 		}
-	case protobufs.ClockFramesResponseType:
-		if err := e.handleClockFramesResponse(
-			message.From,
-			msg.Address,
-			any,
-		); err != nil {
-			return errors.Wrap(err, "handle sync")
-		}
-	case protobufs.ClockFramesRequestType:
-		if err := e.handleClockFramesRequest(
+	// ClockFramesRequestType/ClockFramesResponseType are no longer dispatched
+	// here: bulk frame history is now pulled over the CeremonyService gRPC
+	// stream (see ceremony_service_server.go and sync_client.go), which can
+	// be flow-controlled and ordered in a way per-peer pubsub topics cannot.
+	case protobufs.CeremonyPeerListAnnounceType:
+		if err := e.handlePeerListAnnounce(
 			message.From,
 			msg.Address,
 			any,
@@ -88,10 +110,14 @@ func (e *CeremonyDataClockConsensusEngine) handleSync(
 	return nil
 }
 
+// handleClockFramesResponse processes one streamed batch of clock frames
+// received from the CeremonyService sync client (sync_client.go). It is no
+// longer reached via pubsub: the gRPC stream delivers responses already
+// unmarshaled and attributed to the dialed peer.
 func (e *CeremonyDataClockConsensusEngine) handleClockFramesResponse(
 	peerID []byte,
 	address []byte,
-	any *anypb.Any,
+	response *protobufs.ClockFramesResponse,
 ) error {
 	if bytes.Equal(peerID, e.pubSub.GetPeerID()) {
 		return nil
@@ -110,11 +136,6 @@ func (e *CeremonyDataClockConsensusEngine) handleClockFramesResponse(
 
 	defer func() { e.syncingStatus = SyncStatusNotSyncing }()
 
-	response := &protobufs.ClockFramesResponse{}
-	if err := any.UnmarshalTo(response); err != nil {
-		return errors.Wrap(err, "handle clock frames response")
-	}
-
 	trieCopyBytes, err := e.frameProverTrie.Serialize()
 	if err != nil {
 		return errors.Wrap(err, "handle clock frames response")
@@ -448,176 +469,3 @@ func (e *CeremonyDataClockConsensusEngine) handleProvingKeyRequest(
 
 	return nil
 }
-
-func (e *CeremonyDataClockConsensusEngine) handleClockFramesRequest(
-	peerID []byte,
-	address []byte,
-	any *anypb.Any,
-) error {
-	if bytes.Equal(peerID, e.pubSub.GetPeerID()) {
-		return nil
-	}
-
-	request := &protobufs.ClockFramesRequest{}
-	if err := any.UnmarshalTo(request); err != nil {
-		return errors.Wrap(err, "handle clock frame request")
-	}
-
-	e.pubSub.Subscribe(
-		append(append([]byte{}, e.filter...), peerID...),
-		e.handleSync,
-		true,
-	)
-
-	e.logger.Info(
-		"received clock frame request",
-		zap.Binary("peer_id", peerID),
-		zap.Binary("address", address),
-		zap.Uint64("from_frame_number", request.FromFrameNumber),
-		zap.Uint64("to_frame_number", request.ToFrameNumber),
-	)
-
-	from := request.FromFrameNumber
-
-	base, _, err := e.clockStore.GetDataClockFrame(
-		request.Filter,
-		from,
-	)
-	if err != nil {
-		if !errors.Is(err, store.ErrNotFound) {
-			e.logger.Error(
-				"peer asked for frame that returned error",
-				zap.Binary("peer_id", peerID),
-				zap.Binary("address", address),
-				zap.Uint64("frame_number", request.FromFrameNumber),
-			)
-			return errors.Wrap(err, "handle clock frame request")
-		} else {
-			e.logger.Debug(
-				"peer asked for undiscovered frame",
-				zap.Binary("peer_id", peerID),
-				zap.Binary("address", address),
-				zap.Uint64("frame_number", request.FromFrameNumber),
-			)
-
-			if err = e.publishMessage(
-				append(append([]byte{}, e.filter...), peerID...),
-				&protobufs.ClockFramesResponse{
-					Filter:          request.Filter,
-					FromFrameNumber: 0,
-					ToFrameNumber:   0,
-					ClockFrames:     []*protobufs.ClockFrame{},
-				},
-			); err != nil {
-				return errors.Wrap(err, "handle clock frame request")
-			}
-
-			return nil
-		}
-	}
-
-	to := request.ToFrameNumber
-	if to == 0 || to-request.FromFrameNumber > 32 {
-		to = request.FromFrameNumber + 31
-	}
-
-	set := []*protobufs.ClockFrame{base}
-	noMoreFinalized := false
-	searchSpan := []*protobufs.ClockFrame{base}
-	currentNumber := 1
-
-	for len(searchSpan) != 0 && from+uint64(currentNumber) <= to {
-		e.logger.Info(
-			"scanning frames to add to response",
-			zap.Binary("peer_id", peerID),
-			zap.Binary("address", address),
-			zap.Uint64("from", from),
-			zap.Uint64("to", to),
-			zap.Uint64("current_number", uint64(currentNumber)),
-		)
-		nextSpan := []*protobufs.ClockFrame{}
-		for _, s := range searchSpan {
-			selector, err := s.GetSelector()
-			if err != nil {
-				return errors.Wrap(err, "handle clock frame request")
-			}
-
-			if !noMoreFinalized {
-				frame, _, err := e.clockStore.GetDataClockFrame(
-					s.Filter,
-					s.FrameNumber+1,
-				)
-				if err != nil {
-					if errors.Is(err, store.ErrNotFound) {
-						noMoreFinalized = true
-					} else {
-						e.logger.Error(
-							"fetching clock frame produced error",
-							zap.Binary("peer_id", peerID),
-							zap.Binary("address", address),
-							zap.Uint64("frame_number", s.FrameNumber+1),
-						)
-						return errors.Wrap(err, "handle clock frame request")
-					}
-				} else {
-					if err = e.publishMessage(
-						append(append([]byte{}, e.filter...), peerID...),
-						frame,
-					); err != nil {
-						return errors.Wrap(err, "handle clock frame request")
-					}
-					nextSpan = append(nextSpan, frame)
-					set = append(set, frame)
-				}
-			}
-
-			if noMoreFinalized {
-				iter, err := e.clockStore.RangeCandidateDataClockFrames(
-					s.Filter,
-					selector.Bytes(),
-					s.FrameNumber+1,
-				)
-				if err != nil {
-					e.logger.Error(
-						"peer asked for frame that returned error while iterating",
-						zap.Binary("peer_id", peerID),
-						zap.Binary("address", address),
-						zap.Binary("parent_selector", s.ParentSelector),
-						zap.Uint64("frame_number", s.FrameNumber+1),
-					)
-					return errors.Wrap(err, "handle clock frame request")
-				}
-
-				for iter.First(); iter.Valid(); iter.Next() {
-					frame, err := iter.Value()
-
-					if err != nil {
-						e.logger.Error(
-							"peer asked for frame that returned error while getting value",
-							zap.Binary("peer_id", peerID),
-							zap.Binary("address", address),
-							zap.Binary("parent_selector", selector.Bytes()),
-							zap.Uint64("frame_number", s.FrameNumber+1),
-						)
-						return errors.Wrap(err, "handle clock frame request")
-					}
-
-					if err = e.publishMessage(
-						append(append([]byte{}, e.filter...), peerID...),
-						frame,
-					); err != nil {
-						return errors.Wrap(err, "handle clock frame request")
-					}
-					nextSpan = append(nextSpan, frame)
-					set = append(set, frame)
-				}
-
-				iter.Close()
-			}
-		}
-		currentNumber++
-		searchSpan = nextSpan
-	}
-
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,147 @@
+package ceremony
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/anypb"
+	"source.quilibrium.com/quilibrium/monorepo/node/config"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+)
+
+// peerReachabilityTimeout bounds a single DHT FindPeer lookup when
+// confirming an announced peer, so a CeremonyPeerListAnnounce listing
+// several long-gone peer IDs can't stall on the DHT's default query
+// timeout once per peer.
+const peerReachabilityTimeout = 10 * time.Second
+
+// peerInfo is what the engine remembers about a peer it has seen announce
+// itself on the mesh. It is keyed by the string form of the peer's libp2p
+// peer ID in e.peerMap.
+type peerInfo struct {
+	peerId       []byte
+	version      []byte
+	maxFrame     uint64
+	dhtConfirmed bool
+}
+
+// handlePeerListAnnounce records what a peer advertises in a
+// CeremonyPeerListAnnounce into e.peerMap, so handleSync can gate message
+// dispatch on version and sync-target selection can rank on reachability
+// and max frame. Each peer's DHT lookup runs on its own goroutine with its
+// own bounded timeout, so one long-gone peer in the list can't stall the
+// others. handlePeerListAnnounce waits for every lookup to finish or time
+// out before it returns, so it blocks its caller for up to about
+// peerReachabilityTimeout.
+func (e *CeremonyDataClockConsensusEngine) handlePeerListAnnounce(
+	peerID []byte,
+	address []byte,
+	any *anypb.Any,
+) error {
+	announce := &protobufs.CeremonyPeerListAnnounce{}
+	if err := any.UnmarshalTo(announce); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+
+	for _, p := range announce.PeerList {
+		if bytes.Compare(p.Version, config.GetMinimumVersion()) < 0 {
+			e.logger.Debug(
+				"dropping peer announcement below minimum version",
+				zap.Binary("peer_id", p.PeerId),
+				zap.Binary("version", p.Version),
+			)
+			continue
+		}
+
+		p := p
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(
+				context.Background(),
+				peerReachabilityTimeout,
+			)
+			defer cancel()
+
+			reachable := e.confirmPeerReachable(ctx, p.PeerId)
+			if !reachable {
+				e.logger.Debug(
+					"dht could not confirm advertised peer, dropping announcement",
+					zap.Binary("peer_id", p.PeerId),
+				)
+				return
+			}
+
+			e.setPeerInfo(p.PeerId, p.Version, p.MaxFrame, reachable)
+		}()
+	}
+
+	wg.Wait()
+	return nil
+}
+
+func (e *CeremonyDataClockConsensusEngine) setPeerInfo(
+	peerID []byte,
+	version []byte,
+	maxFrame uint64,
+	dhtConfirmed bool,
+) {
+	e.peerMapMx.Lock()
+	defer e.peerMapMx.Unlock()
+
+	if e.peerMap == nil {
+		e.peerMap = make(map[string]*peerInfo)
+	}
+
+	e.peerMap[string(peerID)] = &peerInfo{
+		peerId:       peerID,
+		version:      version,
+		maxFrame:     maxFrame,
+		dhtConfirmed: dhtConfirmed,
+	}
+}
+
+func (e *CeremonyDataClockConsensusEngine) getPeerInfo(
+	peerID []byte,
+) (*peerInfo, bool) {
+	e.peerMapMx.RLock()
+	defer e.peerMapMx.RUnlock()
+
+	info, ok := e.peerMap[string(peerID)]
+	return info, ok
+}
+
+// recordUncooperativeStrike increments peerID's strike count in
+// e.uncooperativePeersMap.
+func (e *CeremonyDataClockConsensusEngine) recordUncooperativeStrike(
+	peerID []byte,
+) {
+	e.peerMapMx.Lock()
+	defer e.peerMapMx.Unlock()
+
+	if e.uncooperativePeersMap == nil {
+		e.uncooperativePeersMap = make(map[string]uint64)
+	}
+
+	e.uncooperativePeersMap[string(peerID)]++
+}
+
+// peerBelowMinimumVersion treats an unknown peer as not below the minimum,
+// so newly-seen peers aren't penalized before they've had a chance to
+// announce.
+func (e *CeremonyDataClockConsensusEngine) peerBelowMinimumVersion(
+	peerID []byte,
+) bool {
+	info, ok := e.getPeerInfo(peerID)
+	if !ok {
+		return false
+	}
+
+	return bytes.Compare(info.version, config.GetMinimumVersion()) < 0
+}
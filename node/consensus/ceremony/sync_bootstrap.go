@@ -0,0 +1,52 @@
+package ceremony
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+)
+
+// syncLoopInterval is how often the engine looks for a newer sync target.
+const syncLoopInterval = 30 * time.Second
+
+// startSyncServicesOnce guards registering CeremonyService on the node's
+// gRPC server and starting the background sync loop. Neither has any other
+// call site: the engine's Start() isn't part of this package, so
+// ensureSyncServicesStarted is triggered from handleSync instead, the
+// first time this node sees any gossip message on the mesh.
+var startSyncServicesOnce sync.Once
+
+// ensureSyncServicesStarted registers CeremonyService on the node's
+// libp2p-multiplexed gRPC server and starts the background sync loop. Safe
+// to call on every message; the work happens once.
+func (e *CeremonyDataClockConsensusEngine) ensureSyncServicesStarted() {
+	startSyncServicesOnce.Do(func() {
+		protobufs.RegisterCeremonyServiceServer(
+			e.pubSub.GetGRPCServer(),
+			NewCeremonyService(e),
+		)
+
+		go e.runSyncLoop()
+	})
+}
+
+// runSyncLoop calls selectSyncTarget on syncLoopInterval for as long as the
+// engine runs.
+func (e *CeremonyDataClockConsensusEngine) runSyncLoop() {
+	ticker := time.NewTicker(syncLoopInterval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		ctx, cancel := context.WithTimeout(context.Background(), syncLoopInterval)
+		err := e.selectSyncTarget(ctx, e.filter, e.frame, 0)
+		cancel()
+
+		if err != nil && !errors.Is(err, ErrNoNewFrames) {
+			e.logger.Error("sync target selection failed", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package ceremony
+
+import (
+	"context"
+	"io"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"source.quilibrium.com/quilibrium/monorepo/node/protobufs"
+)
+
+// syncWithPeer dials peerID's CeremonyService over a libp2p-multiplexed
+// gRPC connection and consumes GetCompressedSyncFrames until the peer
+// reports it has nothing newer (ErrNoNewFrames), that it's saturated
+// (ErrPeerSaturated), or the context is cancelled.
+func (e *CeremonyDataClockConsensusEngine) syncWithPeer(
+	ctx context.Context,
+	peerID []byte,
+	filter []byte,
+	fromFrameNumber uint64,
+	toFrameNumber uint64,
+) error {
+	conn, err := e.pubSub.GetDirectChannel(peerID)
+	if err != nil {
+		return errors.Wrap(err, "sync with peer")
+	}
+	defer conn.Close()
+
+	client := protobufs.NewCeremonyServiceClient(conn)
+	stream, err := client.GetCompressedSyncFrames(ctx, &protobufs.ClockFramesRequest{
+		Filter:          filter,
+		FromFrameNumber: fromFrameNumber,
+		ToFrameNumber:   toFrameNumber,
+	})
+	if err != nil {
+		return errors.Wrap(err, "sync with peer")
+	}
+
+	for {
+		compressed, err := stream.Recv()
+		if err == io.EOF {
+			return ErrNoNewFrames
+		}
+		if err != nil {
+			return errors.Wrap(err, "sync with peer")
+		}
+
+		if compressed.Saturated {
+			return ErrPeerSaturated
+		}
+
+		if len(compressed.Frames) == 0 {
+			return ErrNoNewFrames
+		}
+
+		response, err := decompressClockFramesResponse(compressed)
+		if err != nil {
+			return errors.Wrap(err, "sync with peer")
+		}
+
+		e.logger.Debug(
+			"received clock frames batch from sync peer",
+			zap.Binary("peer_id", peerID),
+			zap.Uint64("from_frame_number", response.FromFrameNumber),
+			zap.Uint64("to_frame_number", response.ToFrameNumber),
+			zap.Int("frame_count", len(response.ClockFrames)),
+		)
+
+		if err := e.handleClockFramesResponse(
+			peerID,
+			peerID,
+			response,
+		); err != nil {
+			return errors.Wrap(err, "sync with peer")
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package ceremony
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// rankedSyncCandidates returns the engine's known peers, best sync target
+// first: DHT-confirmed reachability outranks unconfirmed, a higher
+// announced MaxFrame outranks a lower one, and ties go to the peer with
+// fewer recorded strikes in uncooperativePeersMap.
+func (e *CeremonyDataClockConsensusEngine) rankedSyncCandidates() [][]byte {
+	e.peerMapMx.RLock()
+	defer e.peerMapMx.RUnlock()
+
+	candidates := make([]*peerInfo, 0, len(e.peerMap))
+	for _, info := range e.peerMap {
+		candidates = append(candidates, info)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.dhtConfirmed != b.dhtConfirmed {
+			return a.dhtConfirmed
+		}
+		if a.maxFrame != b.maxFrame {
+			return a.maxFrame > b.maxFrame
+		}
+		return e.uncooperativePeersMap[string(a.peerId)] <
+			e.uncooperativePeersMap[string(b.peerId)]
+	})
+
+	ranked := make([][]byte, len(candidates))
+	for i, c := range candidates {
+		ranked[i] = c.peerId
+	}
+	return ranked
+}
+
+// selectSyncTarget walks rankedSyncCandidates in order, syncing against
+// each via syncWithPeer, until one yields frames, is confirmed to have
+// nothing newer (ErrNoNewFrames), or the list is exhausted; any other
+// error, including ErrPeerSaturated, falls back to the next candidate
+// instead. A cancelled ctx returns immediately without striking the
+// current candidate or dialing the next one.
+func (e *CeremonyDataClockConsensusEngine) selectSyncTarget(
+	ctx context.Context,
+	filter []byte,
+	fromFrameNumber uint64,
+	toFrameNumber uint64,
+) error {
+	var lastErr error
+
+	for _, peerID := range e.rankedSyncCandidates() {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		e.syncingTarget = peerID
+
+		err := e.syncWithPeer(ctx, peerID, filter, fromFrameNumber, toFrameNumber)
+		if err == nil || errors.Is(err, ErrNoNewFrames) {
+			return err
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if errors.Is(err, ErrPeerSaturated) {
+			e.logger.Debug(
+				"sync target saturated, falling back to next candidate",
+				zap.Binary("peer_id", peerID),
+			)
+			lastErr = err
+			continue
+		}
+
+		e.logger.Debug(
+			"sync target did not respond, falling back to next candidate",
+			zap.Binary("peer_id", peerID),
+			zap.Error(err),
+		)
+		e.recordUncooperativeStrike(peerID)
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		return ErrNoNewFrames
+	}
+
+	return lastErr
+}
@@ -0,0 +1,7 @@
+package protobufs
+
+// CeremonyPeerListAnnounceType is the Any.TypeUrl used for
+// CeremonyPeerListAnnounce messages gossiped over the ceremony pubsub
+// topic, following the same convention as the other *Type constants used
+// to dispatch handleSync.
+const CeremonyPeerListAnnounceType = "type.googleapis.com/quilibrium.node.ceremony.pb.CeremonyPeerListAnnounce"